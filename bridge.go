@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// BRIDGE-MIB / Q-BRIDGE-MIB OIDs. dot1dBasePortIfIndex maps a bridge port
+// number to the real ifIndex. dot1qTpFdbPort is indexed by
+// dot1qVlanIndex.dot1qTpFdbAddress (a VLAN ID followed by a 6-octet MAC)
+// and gives the bridge port a MAC was learned on; dot1dTpFdbPort is its
+// VLAN-less BRIDGE-MIB counterpart (index is just the 6-octet MAC) for
+// older, non-VLAN-aware bridges that don't implement Q-BRIDGE-MIB at all.
+var (
+	dot1dBasePortIfIndex = ".1.3.6.1.2.1.17.1.4.1.2"
+	dot1qTpFdbPort       = ".1.3.6.1.2.1.17.7.1.2.2.1.2"
+	dot1dTpFdbPort       = ".1.3.6.1.2.1.17.4.3.1.2"
+)
+
+// fdbEntry is one decoded row of dot1qTpFdbTable (or of dot1dTpFdbTable,
+// with vlan left empty), before the bridge port has been resolved to an
+// ifIndex.
+type fdbEntry struct {
+	vlan       string
+	mac        string
+	bridgePort string
+}
+
+// fetchBridgePortToIfIndex walks dot1dBasePortTable and returns
+// bridge-port-number -> ifIndex.
+func fetchBridgePortToIfIndex(snmp *gosnmp.GoSNMP) (map[string]string, error) {
+	walk, err := snmp.WalkAll(dot1dBasePortIfIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error walking dot1dBasePortIfIndex: %v", err)
+	}
+
+	portToIfIndex := make(map[string]string, len(walk))
+	for _, variable := range walk {
+		bridgePort := strings.TrimPrefix(variable.Name, dot1dBasePortIfIndex+".")
+		portToIfIndex[bridgePort] = parseSNMPVariable(variable)
+	}
+	return portToIfIndex, nil
+}
+
+// fetchVlanFDB walks dot1qTpFdbTable and decodes each row's VLAN and MAC
+// address out of the OID index, since dot1qTpFdbPort is the only
+// accessible column - the address itself is not a separate GETable value.
+func fetchVlanFDB(snmp *gosnmp.GoSNMP) ([]fdbEntry, error) {
+	walk, err := snmp.WalkAll(dot1qTpFdbPort)
+	if err != nil {
+		return nil, fmt.Errorf("error walking dot1qTpFdbTable: %v", err)
+	}
+
+	entries := make([]fdbEntry, 0, len(walk))
+	for i := range walk {
+		variable := &walk[i]
+		suffix := strings.TrimPrefix(variable.Name, dot1qTpFdbPort+".")
+		parts := strings.SplitN(suffix, ".", 7)
+		if len(parts) != 7 {
+			continue
+		}
+
+		mac, ok := macFromIndexParts(parts[1:])
+		if !ok {
+			continue
+		}
+
+		entries = append(entries, fdbEntry{
+			vlan:       parts[0],
+			mac:        mac,
+			bridgePort: parseSNMPVariable(*variable),
+		})
+	}
+	return entries, nil
+}
+
+// fetchBridgeFDB walks dot1dTpFdbTable (BRIDGE-MIB), the fallback for
+// switches that don't expose Q-BRIDGE-MIB's per-VLAN dot1qTpFdbTable.
+// Entries carry no VLAN, since plain BRIDGE-MIB predates 802.1Q.
+func fetchBridgeFDB(snmp *gosnmp.GoSNMP) ([]fdbEntry, error) {
+	walk, err := snmp.WalkAll(dot1dTpFdbPort)
+	if err != nil {
+		return nil, fmt.Errorf("error walking dot1dTpFdbTable: %v", err)
+	}
+
+	entries := make([]fdbEntry, 0, len(walk))
+	for i := range walk {
+		variable := &walk[i]
+		suffix := strings.TrimPrefix(variable.Name, dot1dTpFdbPort+".")
+		mac, ok := macFromIndexParts(strings.Split(suffix, "."))
+		if !ok {
+			continue
+		}
+
+		entries = append(entries, fdbEntry{
+			mac:        mac,
+			bridgePort: parseSNMPVariable(*variable),
+		})
+	}
+	return entries, nil
+}
+
+// macFromIndexParts turns the six decimal OID components of a MAC-address
+// index suffix into "aa:bb:cc:dd:ee:ff".
+func macFromIndexParts(parts []string) (string, bool) {
+	if len(parts) != 6 {
+		return "", false
+	}
+	octets := make([]string, 6)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || n > 255 {
+			return "", false
+		}
+		octets[i] = fmt.Sprintf("%02x", n)
+	}
+	return strings.Join(octets, ":"), true
+}
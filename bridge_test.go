@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestMacFromIndexParts(t *testing.T) {
+	cases := []struct {
+		name   string
+		parts  []string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "six valid octets",
+			parts:  []string{"0", "26", "43", "60", "77", "94"},
+			want:   "00:1a:2b:3c:4d:5e",
+			wantOK: true,
+		},
+		{
+			name:  "wrong number of parts",
+			parts: []string{"0", "26", "43"},
+		},
+		{
+			name:  "non-numeric part",
+			parts: []string{"0", "26", "43", "60", "77", "oops"},
+		},
+		{
+			name:  "octet out of range",
+			parts: []string{"0", "26", "43", "60", "77", "256"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := macFromIndexParts(tc.parts)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && got != tc.want {
+				t.Errorf("mac = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
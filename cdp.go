@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// CDP (CISCO-CDP-MIB) OIDs. cdpCacheTable is indexed by
+// cdpCacheIfIndex.cdpCacheDeviceIndex.
+var (
+	cdpCacheTable      = ".1.3.6.1.4.1.9.9.23.1.2.1"
+	cdpCacheAddress    = ".1.3.6.1.4.1.9.9.23.1.2.1.1.4"
+	cdpCacheVersion    = ".1.3.6.1.4.1.9.9.23.1.2.1.1.5"
+	cdpCacheDeviceId   = ".1.3.6.1.4.1.9.9.23.1.2.1.1.6"
+	cdpCacheDevicePort = ".1.3.6.1.4.1.9.9.23.1.2.1.1.7"
+	cdpCachePlatform   = ".1.3.6.1.4.1.9.9.23.1.2.1.1.8"
+	cdpCacheNativeVLAN = ".1.3.6.1.4.1.9.9.23.1.2.1.1.11"
+)
+
+var cdpCacheColumnOIDs = []string{
+	cdpCacheAddress,
+	cdpCacheVersion,
+	cdpCacheDeviceId,
+	cdpCacheDevicePort,
+	cdpCachePlatform,
+	cdpCacheNativeVLAN,
+}
+
+type cdpCacheColumns struct {
+	address    *gosnmp.SnmpPDU
+	version    *gosnmp.SnmpPDU
+	deviceId   *gosnmp.SnmpPDU
+	devicePort *gosnmp.SnmpPDU
+	platform   *gosnmp.SnmpPDU
+	nativeVLAN *gosnmp.SnmpPDU
+}
+
+// fetchRemoteCDP walks cdpCacheTable for devices that don't populate
+// LLDP-MIB but do speak CISCO-CDP-MIB, normalizing the result into the
+// same RemoteNeighbor shape fetchRemoteLLDP produces. cdpCacheDeviceId
+// stands in for chassis ID since CDP has no separate chassis identifier.
+func fetchRemoteCDP(snmp *gosnmp.GoSNMP) ([]RemoteNeighbor, error) {
+	walk, err := snmp.WalkAll(cdpCacheTable)
+	if err != nil {
+		return nil, fmt.Errorf("error getting CDP cache table: %v", err)
+	}
+
+	buckets := make(map[remoteIndex]*cdpCacheColumns)
+	var order []remoteIndex
+
+	for i := range walk {
+		variable := &walk[i]
+		debugWalk(fmt.Sprintf("OID: %s, Value: %s", variable.Name, parseSNMPVariable(*variable)))
+
+		column, idx, ok := classifyColumn(variable.Name, cdpCacheColumnOIDs, 2)
+		if !ok {
+			continue
+		}
+
+		entry, ok := buckets[idx]
+		if !ok {
+			entry = &cdpCacheColumns{}
+			buckets[idx] = entry
+			order = append(order, idx)
+		}
+
+		switch column {
+		case cdpCacheAddress:
+			entry.address = variable
+		case cdpCacheVersion:
+			entry.version = variable
+		case cdpCacheDeviceId:
+			entry.deviceId = variable
+		case cdpCacheDevicePort:
+			entry.devicePort = variable
+		case cdpCachePlatform:
+			entry.platform = variable
+		case cdpCacheNativeVLAN:
+			entry.nativeVLAN = variable
+		}
+	}
+
+	results := make([]RemoteNeighbor, 0, len(order))
+	for _, idx := range order {
+		results = append(results, decodeCDPNeighbor(idx, buckets[idx]))
+	}
+	return results, nil
+}
+
+func decodeCDPNeighbor(idx remoteIndex, cols *cdpCacheColumns) RemoteNeighbor {
+	neighbor := RemoteNeighbor{
+		Protocol:     "CDP",
+		LocalPortNum: idx.timeMark, // cdpCacheIfIndex, reusing remoteIndex.timeMark as the first index component
+		Extra:        make(map[string]string),
+	}
+
+	if cols.deviceId != nil {
+		neighbor.ChassisID = parseSNMPVariable(*cols.deviceId)
+		neighbor.SysName = neighbor.ChassisID
+		neighbor.Extra["cdpCacheDeviceId"] = neighbor.ChassisID
+	}
+	if cols.devicePort != nil {
+		neighbor.PortID = parseSNMPVariable(*cols.devicePort)
+	}
+	if cols.platform != nil {
+		neighbor.Extra["cdpCachePlatform"] = parseSNMPVariable(*cols.platform)
+	}
+	if cols.version != nil {
+		neighbor.Extra["cdpCacheVersion"] = parseSNMPVariable(*cols.version)
+	}
+	if cols.nativeVLAN != nil {
+		neighbor.Extra["cdpCacheNativeVLAN"] = parseSNMPVariable(*cols.nativeVLAN)
+	}
+	if cols.address != nil {
+		neighbor.ManagementAddress = decodeCDPAddress(cols.address)
+	}
+
+	return neighbor
+}
+
+// decodeCDPAddress renders a cdpCacheAddress value (raw 4-byte IPv4 in
+// most deployments) as dotted-quad, falling back to the generic decode.
+func decodeCDPAddress(variable *gosnmp.SnmpPDU) string {
+	if raw, ok := variable.Value.([]byte); ok && len(raw) == 4 {
+		return net.IP(raw).String()
+	}
+	return parseSNMPVariable(*variable)
+}
+
+// classifyColumn matches oid against one of columns and splits off an
+// indexWidth-component index suffix, reusing the lldp.go remoteIndex type
+// as a generic (first, second, third) index tuple.
+func classifyColumn(oid string, columns []string, indexWidth int) (string, remoteIndex, bool) {
+	for _, column := range columns {
+		if !strings.HasPrefix(oid, column+".") {
+			continue
+		}
+		suffix := strings.TrimPrefix(oid, column+".")
+		parts := strings.SplitN(suffix, ".", indexWidth+1)
+		if len(parts) < indexWidth {
+			return "", remoteIndex{}, false
+		}
+		idx := remoteIndex{timeMark: parts[0]}
+		if indexWidth >= 2 {
+			idx.localPort = parts[1]
+		}
+		if indexWidth >= 3 {
+			idx.index = parts[2]
+		}
+		return column, idx, true
+	}
+	return "", remoteIndex{}, false
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// V3Profile holds SNMPv3 USM credentials for one named profile, resolved
+// from the credentials config file by the profile name a CSV row gives in
+// its v3profile column; credentials cannot be given inline on a CSV row.
+// AuthProtocol/PrivProtocol use the same names gosnmp does (MD5, SHA,
+// SHA256, SHA512, DES, AES, AES192, AES256).
+type V3Profile struct {
+	Name           string
+	SecurityName   string
+	AuthProtocol   string
+	AuthPassphrase string
+	PrivProtocol   string
+	PrivPassphrase string
+	ContextName    string
+}
+
+// CredentialStore is a set of named v3 profiles loaded from a config file.
+type CredentialStore struct {
+	profiles map[string]V3Profile
+}
+
+// loadCredentials reads a small TOML subset of the form:
+//
+//	[profiles.site1]
+//	security_name = "admin"
+//	auth_protocol = "SHA"
+//	auth_passphrase = "secret1"
+//	priv_protocol = "AES"
+//	priv_passphrase = "secret2"
+//	context_name = ""
+//
+// one [profiles.<name>] section per v3 profile. It intentionally does not
+// pull in a full TOML library since this is the only structured config the
+// tool needs.
+func loadCredentials(path string) (*CredentialStore, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening credentials config %s: %v", path, err)
+	}
+	defer file.Close()
+
+	store := &CredentialStore{profiles: make(map[string]V3Profile)}
+	var current *V3Profile
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if current != nil {
+				store.profiles[current.Name] = *current
+			}
+			section := strings.Trim(text, "[]")
+			name, ok := strings.CutPrefix(section, "profiles.")
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: unrecognized section %q", path, line, text)
+			}
+			current = &V3Profile{Name: strings.Trim(name, `"`)}
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("%s:%d: key outside of any [profiles.*] section", path, line)
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value, got %q", path, line, text)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+
+		switch key {
+		case "security_name":
+			current.SecurityName = value
+		case "auth_protocol":
+			current.AuthProtocol = value
+		case "auth_passphrase":
+			current.AuthPassphrase = value
+		case "priv_protocol":
+			current.PrivProtocol = value
+		case "priv_passphrase":
+			current.PrivPassphrase = value
+		case "context_name":
+			current.ContextName = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unrecognized key %q", path, line, key)
+		}
+	}
+	if current != nil {
+		store.profiles[current.Name] = *current
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading credentials config %s: %v", path, err)
+	}
+	return store, nil
+}
+
+func (s *CredentialStore) lookup(name string) (V3Profile, error) {
+	if s == nil {
+		return V3Profile{}, fmt.Errorf("no credentials config loaded, cannot resolve v3 profile %q", name)
+	}
+	profile, ok := s.profiles[name]
+	if !ok {
+		return V3Profile{}, fmt.Errorf("v3 profile %q not found in credentials config", name)
+	}
+	return profile, nil
+}
+
+func parseAuthProtocol(s string) (gosnmp.SnmpV3AuthProtocol, error) {
+	switch strings.ToUpper(s) {
+	case "", "NOAUTH":
+		return gosnmp.NoAuth, nil
+	case "MD5":
+		return gosnmp.MD5, nil
+	case "SHA":
+		return gosnmp.SHA, nil
+	case "SHA256":
+		return gosnmp.SHA256, nil
+	case "SHA512":
+		return gosnmp.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unrecognized auth protocol %q", s)
+	}
+}
+
+func parsePrivProtocol(s string) (gosnmp.SnmpV3PrivProtocol, error) {
+	switch strings.ToUpper(s) {
+	case "", "NOPRIV":
+		return gosnmp.NoPriv, nil
+	case "DES":
+		return gosnmp.DES, nil
+	case "AES":
+		return gosnmp.AES, nil
+	case "AES192":
+		return gosnmp.AES192, nil
+	case "AES256":
+		return gosnmp.AES256, nil
+	default:
+		return 0, fmt.Errorf("unrecognized priv protocol %q", s)
+	}
+}
+
+// usmSecurityParameters builds the gosnmp USM parameters and the matching
+// MsgFlags for a resolved v3 profile.
+func usmSecurityParameters(profile V3Profile) (*gosnmp.UsmSecurityParameters, gosnmp.SnmpV3MsgFlags, error) {
+	authProtocol, err := parseAuthProtocol(profile.AuthProtocol)
+	if err != nil {
+		return nil, 0, err
+	}
+	privProtocol, err := parsePrivProtocol(profile.PrivProtocol)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	flags := gosnmp.NoAuthNoPriv
+	if authProtocol != gosnmp.NoAuth {
+		flags = gosnmp.AuthNoPriv
+	}
+	if privProtocol != gosnmp.NoPriv {
+		flags = gosnmp.AuthPriv
+	}
+
+	params := &gosnmp.UsmSecurityParameters{
+		UserName:                 profile.SecurityName,
+		AuthenticationProtocol:   authProtocol,
+		AuthenticationPassphrase: profile.AuthPassphrase,
+		PrivacyProtocol:          privProtocol,
+		PrivacyPassphrase:        profile.PrivPassphrase,
+	}
+	return params, flags, nil
+}
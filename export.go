@@ -0,0 +1,27 @@
+package main
+
+import "fmt"
+
+// Exporter writes a completed poll run (local info per target, remote
+// neighbors per target) out to filename in some format. Implementations
+// live one per file: export_csv.go, export_json.go, export_influx.go,
+// export_prometheus.go.
+type Exporter interface {
+	Export(filename string, localInfo map[string]*LocalInventory, remoteInfo map[string][]RemoteNeighbor) error
+}
+
+// exporterFor resolves the -format flag value to an Exporter.
+func exporterFor(format string) (Exporter, error) {
+	switch format {
+	case "", "csv":
+		return CSVExporter{}, nil
+	case "json":
+		return JSONExporter{}, nil
+	case "influx":
+		return InfluxExporter{}, nil
+	case "prometheus":
+		return PrometheusExporter{}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized output format %q (want csv, json, influx, or prometheus)", format)
+	}
+}
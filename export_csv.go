@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CSVExporter writes one row per (target, field) pair, matching the
+// tool's original output shape.
+type CSVExporter struct{}
+
+func (CSVExporter) Export(filename string, localInfo map[string]*LocalInventory, remoteInfo map[string][]RemoteNeighbor) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{"Type", "Target", "Description", "Value"}
+	writer.Write(headers)
+
+	for target, inventory := range localInfo {
+		for desc, value := range inventory.Scalar {
+			writer.Write([]string{"Local", target, desc, value})
+		}
+		for _, port := range inventory.Ports {
+			for desc, value := range portFields(port) {
+				if value == "" {
+					continue
+				}
+				writer.Write([]string{"Local", target, desc, value})
+			}
+		}
+	}
+
+	for target, neighbors := range remoteInfo {
+		for _, neighbor := range neighbors {
+			for desc, value := range neighborFields(neighbor) {
+				if value == "" {
+					continue
+				}
+				writer.Write([]string{"Remote", target, desc, value})
+			}
+		}
+	}
+	return nil
+}
+
+// neighborFields flattens a RemoteNeighbor into the description/value
+// pairs the CSV and future row-oriented exporters use. Protocol-specific
+// Extra fields (e.g. CDP's cdpCachePlatform) are included as their own
+// pairs, keyed by the Extra field name.
+func neighborFields(neighbor RemoteNeighbor) map[string]string {
+	fields := map[string]string{
+		"Protocol":                   neighbor.Protocol,
+		"Local Port Number":          neighbor.LocalPortNum,
+		"Remote Chassis ID":          neighbor.ChassisID,
+		"Remote Port ID":             neighbor.PortID,
+		"Remote Port Description":    neighbor.PortDescription,
+		"Remote System Name":         neighbor.SysName,
+		"Remote System Capabilities": strings.Join(neighbor.Capabilities, ";"),
+		"Remote Management Address":  neighbor.ManagementAddress,
+	}
+	for key, value := range neighbor.Extra {
+		fields[key] = value
+	}
+	return fields
+}
+
+// portFields flattens one LocalPortInventory into description/value
+// pairs, prefixed with its ifIndex so rows for different ports don't
+// collide in the CSV's flat Description column.
+func portFields(port LocalPortInventory) map[string]string {
+	prefix := fmt.Sprintf("Port %s ", port.IfIndex)
+	return map[string]string{
+		prefix + "Description":       port.Descr,
+		prefix + "Alias":             port.Alias,
+		prefix + "Oper Status":       port.OperStatus,
+		prefix + "Speed (Mbps)":      port.HighSpeedMbps,
+		prefix + "LLDP Port Desc":    port.LLDPPortDesc,
+		prefix + "MAC/VLAN Bindings": formatMACBindings(port.MACBindings),
+	}
+}
+
+func formatMACBindings(bindings []MACBinding) string {
+	parts := make([]string, len(bindings))
+	for i, binding := range bindings {
+		parts[i] = fmt.Sprintf("%s@vlan%s", binding.Address, binding.VLAN)
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// InfluxExporter writes InfluxDB line protocol: one lldp_neighbor point
+// per remote neighbor (tags: target, local_port, protocol; fields: the
+// remaining neighbor data) and one lldp_local_mac point per learned MAC
+// binding on a local port. Points are batched through a buffered writer
+// rather than written to the file one at a time, the same batching shape
+// the gosnmp examples use for bulk stats.
+type InfluxExporter struct{}
+
+const (
+	influxMeasurement    = "lldp_neighbor"
+	influxMACMeasurement = "lldp_local_mac"
+)
+
+func (InfluxExporter) Export(filename string, localInfo map[string]*LocalInventory, remoteInfo map[string][]RemoteNeighbor) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	batch := bufio.NewWriter(file)
+	defer batch.Flush()
+
+	for target, inventory := range localInfo {
+		for _, port := range inventory.Ports {
+			for _, binding := range port.MACBindings {
+				if _, err := batch.WriteString(influxMACLine(target, port, binding)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for target, neighbors := range remoteInfo {
+		for _, neighbor := range neighbors {
+			if _, err := batch.WriteString(influxLine(target, neighbor)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func influxMACLine(target string, port LocalPortInventory, binding MACBinding) string {
+	tags := fmt.Sprintf("%s,target=%s,if_index=%s,vlan=%s",
+		influxMACMeasurement, influxEscapeTag(target), influxEscapeTag(port.IfIndex), influxEscapeTag(binding.VLAN))
+	return fmt.Sprintf("%s mac=%s\n", tags, influxEscapeField(binding.Address))
+}
+
+func influxLine(target string, neighbor RemoteNeighbor) string {
+	tags := fmt.Sprintf("%s,target=%s,local_port=%s,protocol=%s",
+		influxMeasurement, influxEscapeTag(target), influxEscapeTag(neighbor.LocalPortNum), influxEscapeTag(neighbor.Protocol))
+
+	fields := []string{
+		fmt.Sprintf("chassis_id=%s", influxEscapeField(neighbor.ChassisID)),
+		fmt.Sprintf("port_id=%s", influxEscapeField(neighbor.PortID)),
+	}
+	if neighbor.SysName != "" {
+		fields = append(fields, fmt.Sprintf("sys_name=%s", influxEscapeField(neighbor.SysName)))
+	}
+	if neighbor.PortDescription != "" {
+		fields = append(fields, fmt.Sprintf("port_description=%s", influxEscapeField(neighbor.PortDescription)))
+	}
+	if neighbor.ManagementAddress != "" {
+		fields = append(fields, fmt.Sprintf("management_address=%s", influxEscapeField(neighbor.ManagementAddress)))
+	}
+	if len(neighbor.Capabilities) > 0 {
+		fields = append(fields, fmt.Sprintf("capabilities=%s", influxEscapeField(strings.Join(neighbor.Capabilities, ";"))))
+	}
+	// Extra holds protocol-specific columns (e.g. CDP's cdpCachePlatform)
+	// that don't fit the common RemoteNeighbor fields above; fold them in
+	// as their own line-protocol fields rather than dropping them.
+	for _, key := range sortedKeys(neighbor.Extra) {
+		fields = append(fields, fmt.Sprintf("%s=%s", key, influxEscapeField(neighbor.Extra[key])))
+	}
+
+	return fmt.Sprintf("%s %s\n", tags, strings.Join(fields, ","))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func influxEscapeTag(s string) string {
+	replacer := strings.NewReplacer(" ", `\ `, ",", `\,`, "=", `\=`)
+	return replacer.Replace(s)
+}
+
+func influxEscapeField(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(s) + `"`
+}
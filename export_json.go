@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// JSONExporter writes newline-delimited JSON, one object per local-info
+// record, local port, or remote neighbor.
+type JSONExporter struct{}
+
+type jsonLocalRecord struct {
+	Type   string            `json:"type"`
+	Target string            `json:"target"`
+	Fields map[string]string `json:"fields"`
+}
+
+type jsonPortRecord struct {
+	Type         string       `json:"type"`
+	Target       string       `json:"target"`
+	IfIndex      string       `json:"if_index"`
+	Descr        string       `json:"descr,omitempty"`
+	Alias        string       `json:"alias,omitempty"`
+	OperStatus   string       `json:"oper_status,omitempty"`
+	SpeedMbps    string       `json:"speed_mbps,omitempty"`
+	LLDPPortDesc string       `json:"lldp_port_desc,omitempty"`
+	MACBindings  []MACBinding `json:"mac_bindings,omitempty"`
+}
+
+type jsonRemoteRecord struct {
+	Type              string            `json:"type"`
+	Target            string            `json:"target"`
+	Protocol          string            `json:"protocol"`
+	LocalPortNum      string            `json:"local_port"`
+	ChassisID         string            `json:"chassis_id"`
+	PortID            string            `json:"port_id"`
+	PortDescription   string            `json:"port_description,omitempty"`
+	SysName           string            `json:"sys_name,omitempty"`
+	Capabilities      []string          `json:"capabilities,omitempty"`
+	ManagementAddress string            `json:"management_address,omitempty"`
+	Extra             map[string]string `json:"extra,omitempty"`
+}
+
+func (JSONExporter) Export(filename string, localInfo map[string]*LocalInventory, remoteInfo map[string][]RemoteNeighbor) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+
+	for target, inventory := range localInfo {
+		record := jsonLocalRecord{Type: "local", Target: target, Fields: inventory.Scalar}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+
+		for _, port := range inventory.Ports {
+			portRecord := jsonPortRecord{
+				Type:         "local_port",
+				Target:       target,
+				IfIndex:      port.IfIndex,
+				Descr:        port.Descr,
+				Alias:        port.Alias,
+				OperStatus:   port.OperStatus,
+				SpeedMbps:    port.HighSpeedMbps,
+				LLDPPortDesc: port.LLDPPortDesc,
+				MACBindings:  port.MACBindings,
+			}
+			if err := encoder.Encode(portRecord); err != nil {
+				return err
+			}
+		}
+	}
+
+	for target, neighbors := range remoteInfo {
+		for _, neighbor := range neighbors {
+			record := jsonRemoteRecord{
+				Type:              "remote",
+				Target:            target,
+				Protocol:          neighbor.Protocol,
+				LocalPortNum:      neighbor.LocalPortNum,
+				ChassisID:         neighbor.ChassisID,
+				PortID:            neighbor.PortID,
+				PortDescription:   neighbor.PortDescription,
+				SysName:           neighbor.SysName,
+				Capabilities:      neighbor.Capabilities,
+				ManagementAddress: neighbor.ManagementAddress,
+				Extra:             neighbor.Extra,
+			}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PrometheusExporter writes a node-exporter textfile collector file: an
+// info-style gauge (always 1) carrying the neighbor data as labels, so
+// operators can point node_exporter's --collector.textfile.directory at
+// it and get link-neighbor metrics without any post-processing.
+type PrometheusExporter struct{}
+
+const prometheusMetricName = "lldp_neighbor_info"
+
+func (PrometheusExporter) Export(filename string, localInfo map[string]*LocalInventory, remoteInfo map[string][]RemoteNeighbor) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	defer writer.Flush()
+
+	fmt.Fprintf(writer, "# HELP %s Discovered link-layer neighbor (always 1).\n", prometheusMetricName)
+	fmt.Fprintf(writer, "# TYPE %s gauge\n", prometheusMetricName)
+
+	for target, neighbors := range remoteInfo {
+		for _, neighbor := range neighbors {
+			labels := []string{
+				prometheusLabel("target", target),
+				prometheusLabel("local_port", neighbor.LocalPortNum),
+				prometheusLabel("protocol", neighbor.Protocol),
+				prometheusLabel("chassis_id", neighbor.ChassisID),
+				prometheusLabel("port_id", neighbor.PortID),
+				prometheusLabel("sys_name", neighbor.SysName),
+			}
+			// Extra holds protocol-specific columns (e.g. CDP's
+			// cdpCachePlatform) that don't fit the common labels above;
+			// fold them in as their own labels rather than dropping them.
+			for _, key := range sortedKeys(neighbor.Extra) {
+				labels = append(labels, prometheusLabel(key, neighbor.Extra[key]))
+			}
+			fmt.Fprintf(writer, "%s{%s} 1\n", prometheusMetricName, strings.Join(labels, ","))
+		}
+	}
+	return nil
+}
+
+func prometheusLabel(name, value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return fmt.Sprintf(`%s="%s"`, name, replacer.Replace(value))
+}
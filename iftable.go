@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// ifTable/ifXTable (IF-MIB) columns, each indexed by plain ifIndex.
+var (
+	ifDescr      = ".1.3.6.1.2.1.2.2.1.2"
+	ifOperStatus = ".1.3.6.1.2.1.2.2.1.8"
+	ifAlias      = ".1.3.6.1.2.1.31.1.1.1.18"
+	ifHighSpeed  = ".1.3.6.1.2.1.31.1.1.1.15"
+)
+
+// LocalPortInventory is one local interface, enriched with ifTable/ifXTable
+// data, the LLDP-advertised port description, and whatever MAC/VLAN
+// bindings the bridge tables report for it.
+type LocalPortInventory struct {
+	IfIndex       string
+	Descr         string
+	Alias         string
+	OperStatus    string
+	HighSpeedMbps string
+	LLDPPortDesc  string
+	MACBindings   []MACBinding
+}
+
+// MACBinding is one learned MAC address on a local port, with the VLAN it
+// was learned on.
+type MACBinding struct {
+	Address string
+	VLAN    string
+}
+
+// fetchIfTable walks ifTable and ifXTable and returns one LocalPortInventory
+// per ifIndex seen.
+func fetchIfTable(snmp *gosnmp.GoSNMP) (map[string]*LocalPortInventory, error) {
+	ports := make(map[string]*LocalPortInventory)
+
+	ifWalk, err := snmp.WalkAll(".1.3.6.1.2.1.2.2.1")
+	if err != nil {
+		return nil, fmt.Errorf("error walking ifTable: %v", err)
+	}
+	for i := range ifWalk {
+		variable := &ifWalk[i]
+		switch {
+		case strings.HasPrefix(variable.Name, ifDescr+"."):
+			ifIndex := strings.TrimPrefix(variable.Name, ifDescr+".")
+			portFor(ports, ifIndex).Descr = parseSNMPVariable(*variable)
+		case strings.HasPrefix(variable.Name, ifOperStatus+"."):
+			ifIndex := strings.TrimPrefix(variable.Name, ifOperStatus+".")
+			portFor(ports, ifIndex).OperStatus = decodeOperStatus(variable)
+		}
+	}
+
+	ifXWalk, err := snmp.WalkAll(".1.3.6.1.2.1.31.1.1.1")
+	if err != nil {
+		return nil, fmt.Errorf("error walking ifXTable: %v", err)
+	}
+	for i := range ifXWalk {
+		variable := &ifXWalk[i]
+		switch {
+		case strings.HasPrefix(variable.Name, ifAlias+"."):
+			ifIndex := strings.TrimPrefix(variable.Name, ifAlias+".")
+			portFor(ports, ifIndex).Alias = parseSNMPVariable(*variable)
+		case strings.HasPrefix(variable.Name, ifHighSpeed+"."):
+			ifIndex := strings.TrimPrefix(variable.Name, ifHighSpeed+".")
+			portFor(ports, ifIndex).HighSpeedMbps = parseSNMPVariable(*variable)
+		}
+	}
+
+	return ports, nil
+}
+
+// LLDP-MIB lldpLocPortTable columns. lldpLocPortNum (the index of both
+// these and lldpLocPortDesc) is its own numbering space on many devices and
+// is not safe to assume equals ifIndex - portIdSubtype/portId let us
+// resolve it to the real ifIndex the same way renderIdentifier resolves
+// remote chassis/port IDs by their paired subtype column.
+var (
+	lldpLocPortIDSubtype = ".1.0.8802.1.1.2.1.3.7.1.2"
+	lldpLocPortID        = ".1.0.8802.1.1.2.1.3.7.1.3"
+)
+
+const (
+	lldpPortIDSubtypeInterfaceName = 5
+	lldpPortIDSubtypeLocal         = 7
+)
+
+// resolveLocalPortIfIndex maps lldpLocPortNum -> ifIndex by walking
+// lldpLocPortIdSubtype/lldpLocPortId and matching against the ifDescr
+// values already collected in ports. A portNum whose subtype/value can't
+// be correlated to a known ifIndex is left out of the result rather than
+// guessed at, so callers don't create a second, bogus port entry for it.
+func resolveLocalPortIfIndex(snmp *gosnmp.GoSNMP, ports map[string]*LocalPortInventory) (map[string]string, error) {
+	subtypeWalk, err := snmp.WalkAll(lldpLocPortIDSubtype)
+	if err != nil {
+		return nil, fmt.Errorf("error walking lldpLocPortIdSubtype: %v", err)
+	}
+	subtypes := make(map[string]int64, len(subtypeWalk))
+	for i := range subtypeWalk {
+		variable := &subtypeWalk[i]
+		portNum := strings.TrimPrefix(variable.Name, lldpLocPortIDSubtype+".")
+		subtypes[portNum] = gosnmp.ToBigInt(variable.Value).Int64()
+	}
+
+	idWalk, err := snmp.WalkAll(lldpLocPortID)
+	if err != nil {
+		return nil, fmt.Errorf("error walking lldpLocPortId: %v", err)
+	}
+
+	descrToIfIndex := make(map[string]string, len(ports))
+	for ifIndex, port := range ports {
+		descrToIfIndex[port.Descr] = ifIndex
+	}
+
+	portNumToIfIndex := make(map[string]string, len(idWalk))
+	for i := range idWalk {
+		variable := &idWalk[i]
+		portNum := strings.TrimPrefix(variable.Name, lldpLocPortID+".")
+		value := parseSNMPVariable(*variable)
+
+		switch subtypes[portNum] {
+		case lldpPortIDSubtypeLocal:
+			if _, ok := ports[value]; ok {
+				portNumToIfIndex[portNum] = value
+			}
+		case lldpPortIDSubtypeInterfaceName:
+			if ifIndex, ok := descrToIfIndex[value]; ok {
+				portNumToIfIndex[portNum] = ifIndex
+			}
+		}
+	}
+	return portNumToIfIndex, nil
+}
+
+// mergeLocalPortDesc walks lldpLocPortDesc and attaches each description to
+// the port it actually describes, resolving lldpLocPortNum to ifIndex via
+// resolveLocalPortIfIndex rather than assuming the two numbering spaces
+// coincide. A portNum that can't be resolved is skipped (and logged here
+// under the snmp trace subsystem) rather than given a disjoint entry of its
+// own.
+func mergeLocalPortDesc(snmp *gosnmp.GoSNMP, ports map[string]*LocalPortInventory) error {
+	portNumToIfIndex, err := resolveLocalPortIfIndex(snmp, ports)
+	if err != nil {
+		return err
+	}
+
+	descWalk, err := snmp.WalkAll(lldpLocPortDesc)
+	if err != nil {
+		return fmt.Errorf("error walking lldpLocPortDesc: %v", err)
+	}
+
+	for i := range descWalk {
+		variable := &descWalk[i]
+		portNum := strings.TrimPrefix(variable.Name, lldpLocPortDesc+".")
+		ifIndex, ok := portNumToIfIndex[portNum]
+		if !ok {
+			debugSNMP("lldpLocPortNum did not resolve to a known ifIndex", "port_num", portNum)
+			continue
+		}
+		ports[ifIndex].LLDPPortDesc = parseSNMPVariable(*variable)
+	}
+	return nil
+}
+
+func portFor(ports map[string]*LocalPortInventory, ifIndex string) *LocalPortInventory {
+	port, ok := ports[ifIndex]
+	if !ok {
+		port = &LocalPortInventory{IfIndex: ifIndex}
+		ports[ifIndex] = port
+	}
+	return port
+}
+
+func decodeOperStatus(variable *gosnmp.SnmpPDU) string {
+	switch gosnmp.ToBigInt(variable.Value).Int64() {
+	case 1:
+		return "up"
+	case 2:
+		return "down"
+	case 3:
+		return "testing"
+	case 4:
+		return "unknown"
+	case 5:
+		return "dormant"
+	case 6:
+		return "notPresent"
+	case 7:
+		return "lowerLayerDown"
+	default:
+		return parseSNMPVariable(*variable)
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+var (
+	// Local LLDP Information OIDs
+	lldpLocChassisID = ".1.0.8802.1.1.2.1.3.2.0"
+	lldpLocSysName   = ".1.0.8802.1.1.2.1.3.3.0"
+	lldpLocPortDesc  = ".1.0.8802.1.1.2.1.3.7.1.4"
+
+	// Additional OIDs
+	sysDesc   = ".1.3.6.1.2.1.1.1.0"       // System Description
+	sysVendor = ".1.3.6.1.4.1.8072.3.2.10" // sysVendor (assuming for the example)
+
+	// Remote LLDP Information OIDs. lldpRemWalkRoot covers both lldpRemTable
+	// (.4.1) and lldpRemManAddrTable (.4.2) so the management address columns
+	// are actually included in the walk.
+	lldpRemWalkRoot      = ".1.0.8802.1.1.2.1.4"
+	lldpRemChassisIDSub  = ".1.0.8802.1.1.2.1.4.1.1.4"
+	lldpRemChassisID     = ".1.0.8802.1.1.2.1.4.1.1.5"
+	lldpRemPortIDSub     = ".1.0.8802.1.1.2.1.4.1.1.6"
+	lldpRemPortID        = ".1.0.8802.1.1.2.1.4.1.1.7"
+	lldpRemPortDesc      = ".1.0.8802.1.1.2.1.4.1.1.8"
+	lldpRemSysName       = ".1.0.8802.1.1.2.1.4.1.1.9"
+	lldpRemSysCapEnabled = ".1.0.8802.1.1.2.1.4.1.1.12"
+	lldpRemMgmtAddress   = ".1.0.8802.1.1.2.1.4.2.1.4"
+)
+
+// chassisIDSubtype/portIdSubtype values from the LLDP-MIB (IEEE 802.1AB).
+const (
+	chassisIDSubtypeMacAddress = 4
+	portIDSubtypeMacAddress    = 3
+)
+
+// lldpCapabilityNames are the bit names of lldpRemSysCapEnabled/Supported,
+// in bit order (bit 0 = MSB of the first octet), per IEEE 802.1AB.
+var lldpCapabilityNames = []string{
+	"other",
+	"repeater",
+	"bridge",
+	"wlan-ap",
+	"router",
+	"telephone",
+	"docsis",
+	"station-only",
+}
+
+// remoteIndex is the lldpRemTimeMark.lldpRemLocalPortNum.lldpRemIndex
+// triplet that uniquely identifies one remote neighbor entry. Columns are
+// bucketed by this key instead of by arrival order, so a neighbor missing
+// some columns (e.g. no management address) still produces one record.
+type remoteIndex struct {
+	timeMark  string
+	localPort string
+	index     string
+}
+
+// remoteColumns accumulates the raw PDUs seen for one remoteIndex as the
+// walk streams in, before they're decoded into a RemoteNeighbor.
+type remoteColumns struct {
+	chassisIDSubtype *gosnmp.SnmpPDU
+	chassisID        *gosnmp.SnmpPDU
+	portIDSubtype    *gosnmp.SnmpPDU
+	portID           *gosnmp.SnmpPDU
+	portDesc         *gosnmp.SnmpPDU
+	sysName          *gosnmp.SnmpPDU
+	sysCapEnabled    *gosnmp.SnmpPDU
+	mgmtAddress      *gosnmp.SnmpPDU
+}
+
+// RemoteNeighbor is one discovered link-layer neighbor, normalized across
+// discovery protocols (LLDP, CDP and SONMP) so exporters and the
+// topology builder don't need to care which protocol found it.
+type RemoteNeighbor struct {
+	Protocol          string
+	LocalPortNum      string
+	ChassisID         string
+	PortID            string
+	PortDescription   string
+	SysName           string
+	Capabilities      []string
+	ManagementAddress string
+	// Extra holds protocol-specific fields that don't fit the common
+	// shape above, e.g. CDP's cdpCachePlatform/cdpCacheNativeVLAN.
+	Extra map[string]string
+}
+
+// fetchLocalLLDP fetches the scalar local-system identifiers. Per-port
+// data (lldpLocPortDesc and the broader ifTable/FDB enrichment) lives in
+// fetchLocalInventory instead, since lldpLocPortDesc is a table column
+// keyed by lldpLocPortNum rather than a plain scalar.
+func fetchLocalLLDP(snmp *gosnmp.GoSNMP) (map[string]string, error) {
+	localOids := []string{lldpLocChassisID, lldpLocSysName, sysDesc, sysVendor}
+	localInfo, err := snmp.Get(localOids)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local LLDP info: %v", err)
+	}
+
+	result := make(map[string]string)
+	for _, variable := range localInfo.Variables {
+		value := parseSNMPVariable(variable)
+		switch variable.Name {
+		case lldpLocChassisID:
+			result["Local Chassis ID"] = value
+		case lldpLocSysName:
+			result["Local System Name"] = value
+		case sysDesc:
+			result["System Description"] = value
+		case sysVendor:
+			result["System Vendor"] = value
+		}
+	}
+	return result, nil
+}
+
+func fetchRemoteLLDP(snmp *gosnmp.GoSNMP) ([]RemoteNeighbor, error) {
+	walk, err := snmp.WalkAll(lldpRemWalkRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error getting remote LLDP info: %v", err)
+	}
+
+	buckets := make(map[remoteIndex]*remoteColumns)
+	var order []remoteIndex
+
+	for i := range walk {
+		variable := &walk[i]
+		debugWalk(fmt.Sprintf("OID: %s, Value: %s", variable.Name, parseSNMPVariable(*variable)))
+
+		column, idx, ok := classifyRemoteColumn(variable.Name)
+		if !ok {
+			continue
+		}
+
+		entry, ok := buckets[idx]
+		if !ok {
+			entry = &remoteColumns{}
+			buckets[idx] = entry
+			order = append(order, idx)
+		}
+
+		switch column {
+		case lldpRemChassisIDSub:
+			entry.chassisIDSubtype = variable
+		case lldpRemChassisID:
+			entry.chassisID = variable
+		case lldpRemPortIDSub:
+			entry.portIDSubtype = variable
+		case lldpRemPortID:
+			entry.portID = variable
+		case lldpRemPortDesc:
+			entry.portDesc = variable
+		case lldpRemSysName:
+			entry.sysName = variable
+		case lldpRemSysCapEnabled:
+			entry.sysCapEnabled = variable
+		case lldpRemMgmtAddress:
+			entry.mgmtAddress = variable
+		}
+	}
+
+	results := make([]RemoteNeighbor, 0, len(order))
+	for _, idx := range order {
+		results = append(results, decodeRemoteNeighbor(idx, buckets[idx]))
+	}
+	return results, nil
+}
+
+// remoteColumnOIDs are the lldpRem* columns classifyRemoteColumn matches a
+// walked OID against. A column only matches when the OID continues with
+// "." right after it, so e.g. lldpRemChassisIdSubtype (.4) can never be
+// taken for lldpRemChassisId (.5) and the order of this list does not
+// affect the result.
+var remoteColumnOIDs = []string{
+	lldpRemChassisIDSub,
+	lldpRemChassisID,
+	lldpRemPortIDSub,
+	lldpRemPortID,
+	lldpRemPortDesc,
+	lldpRemSysName,
+	lldpRemSysCapEnabled,
+	lldpRemMgmtAddress,
+}
+
+func classifyRemoteColumn(oid string) (column string, idx remoteIndex, ok bool) {
+	return classifyColumn(oid, remoteColumnOIDs, 3)
+}
+
+func decodeRemoteNeighbor(idx remoteIndex, cols *remoteColumns) RemoteNeighbor {
+	neighbor := RemoteNeighbor{
+		Protocol:     "LLDP",
+		LocalPortNum: idx.localPort,
+	}
+
+	if cols.chassisID != nil {
+		neighbor.ChassisID = renderIdentifier(cols.chassisID, cols.chassisIDSubtype, chassisIDSubtypeMacAddress)
+	}
+	if cols.portID != nil {
+		neighbor.PortID = renderIdentifier(cols.portID, cols.portIDSubtype, portIDSubtypeMacAddress)
+	}
+	if cols.portDesc != nil {
+		neighbor.PortDescription = parseSNMPVariable(*cols.portDesc)
+	}
+	if cols.sysName != nil {
+		neighbor.SysName = parseSNMPVariable(*cols.sysName)
+	}
+	if cols.sysCapEnabled != nil {
+		neighbor.Capabilities = decodeCapabilities(cols.sysCapEnabled)
+	}
+	if cols.mgmtAddress != nil {
+		neighbor.ManagementAddress = parseSNMPVariable(*cols.mgmtAddress)
+	}
+
+	return neighbor
+}
+
+// renderIdentifier formats a chassis/port ID column, rendering it as a
+// MAC address when the paired subtype column says it is one.
+func renderIdentifier(value *gosnmp.SnmpPDU, subtype *gosnmp.SnmpPDU, macSubtype int) string {
+	if isSubtype(subtype, macSubtype) {
+		if raw, ok := value.Value.([]byte); ok && len(raw) == 6 {
+			return formatMACAddress(raw)
+		}
+	}
+	return parseSNMPVariable(*value)
+}
+
+func isSubtype(subtype *gosnmp.SnmpPDU, want int) bool {
+	if subtype == nil {
+		return false
+	}
+	return int(gosnmp.ToBigInt(subtype.Value).Int64()) == want
+}
+
+func formatMACAddress(raw []byte) string {
+	parts := make([]string, len(raw))
+	for i, b := range raw {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// decodeCapabilities decodes an lldpRemSysCapEnabled/Supported BITS value
+// into human-readable capability names, per IEEE 802.1AB.
+func decodeCapabilities(variable *gosnmp.SnmpPDU) []string {
+	raw, ok := variable.Value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	var caps []string
+	for i, name := range lldpCapabilityNames {
+		byteIdx := i / 8
+		if byteIdx >= len(raw) {
+			break
+		}
+		bit := byte(0x80) >> uint(i%8)
+		if raw[byteIdx]&bit != 0 {
+			caps = append(caps, name)
+		}
+	}
+	return caps
+}
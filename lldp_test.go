@@ -0,0 +1,149 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+func TestClassifyRemoteColumn(t *testing.T) {
+	cases := []struct {
+		name       string
+		oid        string
+		wantColumn string
+		wantIdx    remoteIndex
+		wantOK     bool
+	}{
+		{
+			name:       "chassis id column",
+			oid:        lldpRemChassisID + ".0.1.2",
+			wantColumn: lldpRemChassisID,
+			wantIdx:    remoteIndex{timeMark: "0", localPort: "1", index: "2"},
+			wantOK:     true,
+		},
+		{
+			name:       "management address column carries a longer trailing address",
+			oid:        lldpRemMgmtAddress + ".0.1.2.1.4.10.0.0.1",
+			wantColumn: lldpRemMgmtAddress,
+			wantIdx:    remoteIndex{timeMark: "0", localPort: "1", index: "2"},
+			wantOK:     true,
+		},
+		{
+			name: "unrelated OID",
+			oid:  ".1.3.6.1.2.1.1.1.0",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			column, idx, ok := classifyRemoteColumn(tc.oid)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if column != tc.wantColumn {
+				t.Errorf("column = %q, want %q", column, tc.wantColumn)
+			}
+			if idx != tc.wantIdx {
+				t.Errorf("idx = %+v, want %+v", idx, tc.wantIdx)
+			}
+		})
+	}
+}
+
+func TestDecodeRemoteNeighbor(t *testing.T) {
+	idx := remoteIndex{timeMark: "0", localPort: "5", index: "1"}
+
+	t.Run("mac-subtype chassis and port id render as MAC addresses", func(t *testing.T) {
+		cols := &remoteColumns{
+			chassisIDSubtype: &gosnmp.SnmpPDU{Value: chassisIDSubtypeMacAddress},
+			chassisID:        &gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}},
+			portIDSubtype:    &gosnmp.SnmpPDU{Value: portIDSubtypeMacAddress},
+			portID:           &gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66}},
+			sysName:          &gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte("switch-1")},
+		}
+
+		neighbor := decodeRemoteNeighbor(idx, cols)
+
+		if neighbor.ChassisID != "aa:bb:cc:dd:ee:ff" {
+			t.Errorf("ChassisID = %q, want aa:bb:cc:dd:ee:ff", neighbor.ChassisID)
+		}
+		if neighbor.PortID != "11:22:33:44:55:66" {
+			t.Errorf("PortID = %q, want 11:22:33:44:55:66", neighbor.PortID)
+		}
+		if neighbor.SysName != "switch-1" {
+			t.Errorf("SysName = %q, want switch-1", neighbor.SysName)
+		}
+		if neighbor.LocalPortNum != "5" {
+			t.Errorf("LocalPortNum = %q, want 5", neighbor.LocalPortNum)
+		}
+	})
+
+	t.Run("non-mac subtype falls back to the generic decode", func(t *testing.T) {
+		cols := &remoteColumns{
+			chassisIDSubtype: &gosnmp.SnmpPDU{Value: 7}, // "local", not a MAC address
+			chassisID:        &gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte("chassis-name")},
+		}
+
+		neighbor := decodeRemoteNeighbor(idx, cols)
+
+		if neighbor.ChassisID != "chassis-name" {
+			t.Errorf("ChassisID = %q, want chassis-name", neighbor.ChassisID)
+		}
+	})
+
+	t.Run("missing optional columns leave zero values instead of panicking", func(t *testing.T) {
+		neighbor := decodeRemoteNeighbor(idx, &remoteColumns{})
+
+		if neighbor.ChassisID != "" || neighbor.PortID != "" || neighbor.SysName != "" {
+			t.Errorf("expected zero-value fields for missing columns, got %+v", neighbor)
+		}
+		if neighbor.Protocol != "LLDP" {
+			t.Errorf("Protocol = %q, want LLDP", neighbor.Protocol)
+		}
+	})
+}
+
+func TestFormatMACAddress(t *testing.T) {
+	got := formatMACAddress([]byte{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e})
+	want := "00:1a:2b:3c:4d:5e"
+	if got != want {
+		t.Errorf("formatMACAddress() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeCapabilities(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  []byte
+		want []string
+	}{
+		{
+			name: "bridge and router bits set",
+			raw:  []byte{0x28},
+			want: []string{"bridge", "router"},
+		},
+		{
+			name: "no bits set",
+			raw:  []byte{0x00},
+			want: nil,
+		},
+		{
+			name: "a second octet beyond the defined capability names is ignored, not panicked on",
+			raw:  []byte{0x80, 0xff},
+			want: []string{"other"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := decodeCapabilities(&gosnmp.SnmpPDU{Value: tc.raw})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("decodeCapabilities(%08b) = %v, want %v", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// LocalInventory is everything fetchLocalInventory learns about the
+// polled target itself: its LLDP scalar identifiers plus one
+// LocalPortInventory per local interface, enriched with ifTable/ifXTable
+// data and the MAC/VLAN bindings learned from the bridge tables.
+type LocalInventory struct {
+	Scalar map[string]string
+	Ports  []LocalPortInventory
+}
+
+// fetchLocalInventory answers "which MACs are behind this uplink and
+// which VLAN is each one on" from a single poll: it fetches the LLDP
+// scalars, walks ifTable/ifXTable, and correlates BRIDGE-MIB/Q-BRIDGE-MIB
+// FDB entries onto the ifIndex they were learned on. Bridge tables are
+// optional - plenty of devices don't expose them - so a failure there
+// degrades to port info without MAC/VLAN data rather than failing the
+// whole target.
+func fetchLocalInventory(snmp *gosnmp.GoSNMP) (*LocalInventory, error) {
+	scalar, err := fetchLocalLLDP(snmp)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := fetchIfTable(snmp)
+	if err != nil {
+		return nil, fmt.Errorf("error enriching local interfaces: %v", err)
+	}
+
+	if err := mergeLocalPortDesc(snmp, ports); err != nil {
+		logger.Warn("lldpLocPortDesc correlation unavailable", "error", err)
+	}
+
+	portToIfIndex, err := fetchBridgePortToIfIndex(snmp)
+	if err != nil {
+		logger.Warn("bridge port to ifIndex mapping unavailable", "error", err)
+		portToIfIndex = nil
+	}
+
+	fdb, err := fetchVlanFDB(snmp)
+	if err != nil {
+		logger.Warn("Q-BRIDGE-MIB FDB unavailable", "error", err)
+		fdb = nil
+	}
+	if len(fdb) == 0 {
+		// Plenty of simple, non-VLAN-aware bridges only expose plain
+		// BRIDGE-MIB, not Q-BRIDGE-MIB - fall back so those targets still
+		// get MAC bindings, just without a VLAN.
+		bridgeFDB, err := fetchBridgeFDB(snmp)
+		if err != nil {
+			logger.Warn("BRIDGE-MIB FDB unavailable", "error", err)
+		}
+		fdb = bridgeFDB
+	}
+
+	for _, entry := range fdb {
+		ifIndex, ok := portToIfIndex[entry.bridgePort]
+		if !ok {
+			continue
+		}
+		port := portFor(ports, ifIndex)
+		port.MACBindings = append(port.MACBindings, MACBinding{Address: entry.mac, VLAN: entry.vlan})
+	}
+
+	ifIndexes := make([]string, 0, len(ports))
+	for ifIndex := range ports {
+		ifIndexes = append(ifIndexes, ifIndex)
+	}
+	sort.Strings(ifIndexes)
+
+	inventory := &LocalInventory{Scalar: scalar, Ports: make([]LocalPortInventory, 0, len(ifIndexes))}
+	for _, ifIndex := range ifIndexes {
+		inventory.Ports = append(inventory.Ports, *ports[ifIndex])
+	}
+	return inventory, nil
+}
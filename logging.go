@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// handlerOptions sets the handler's minimum level to Debug; otherwise the
+// built-in text/json handlers default to LevelInfo and silently drop every
+// debugSNMP/debugWalk/debugParse record before the per-subsystem LLDPTRACE
+// gate in this file ever sees it.
+var handlerOptions = &slog.HandlerOptions{Level: slog.LevelDebug}
+
+// logger is the process-wide structured logger, reconfigured by
+// setupLogger once -log-format has been parsed.
+var logger = slog.New(slog.NewTextHandler(os.Stderr, handlerOptions))
+
+// setupLogger points logger at a handler for the requested format:
+// text and json use slog's built-in handlers, recfile emits GNU
+// recutils-style stanzas (one blank-line-separated record per event,
+// "key: value" lines) so logs stay grep-friendly and line-oriented tools
+// can still parse them.
+func setupLogger(format string) error {
+	var handler slog.Handler
+	switch format {
+	case "", "text":
+		handler = slog.NewTextHandler(os.Stderr, handlerOptions)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, handlerOptions)
+	case "recfile":
+		handler = newRecfileHandler(os.Stderr)
+	default:
+		return fmt.Errorf("unrecognized log format %q (want text, json, or recfile)", format)
+	}
+	logger = slog.New(handler)
+	return nil
+}
+
+// traceFlags gates debugSNMP/debugWalk/debugParse similar to syncthing's
+// STTRACE: LLDPTRACE=snmp,walk,parse turns on only the named subsystems'
+// debug output instead of every debug line in the process.
+type traceFlags struct {
+	subsystems map[string]bool
+}
+
+func parseTraceEnv() traceFlags {
+	flags := traceFlags{subsystems: make(map[string]bool)}
+	for _, name := range strings.Split(os.Getenv("LLDPTRACE"), ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			flags.subsystems[name] = true
+		}
+	}
+	return flags
+}
+
+func (t traceFlags) enabled(subsystem string) bool {
+	return t.subsystems[subsystem]
+}
+
+var trace = parseTraceEnv()
+
+func debugSNMP(msg string, args ...any) {
+	if trace.enabled("snmp") {
+		logger.Debug(msg, args...)
+	}
+}
+
+func debugWalk(msg string, args ...any) {
+	if trace.enabled("walk") {
+		logger.Debug(msg, args...)
+	}
+}
+
+func debugParse(msg string, args ...any) {
+	if trace.enabled("parse") {
+		logger.Debug(msg, args...)
+	}
+}
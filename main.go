@@ -1,256 +1,168 @@
 package main
 
 import (
-	"encoding/csv"
-	"encoding/hex"
+	"flag"
 	"fmt"
-	"log"
 	"os"
-	"strings"
-	"time"
+	"sync"
 
 	"github.com/gosnmp/gosnmp"
 )
 
-var (
-	// Local LLDP Information OIDs
-	lldpLocChassisID = ".1.0.8802.1.1.2.1.3.2.0"
-	lldpLocSysName   = ".1.0.8802.1.1.2.1.3.3.0"
-	lldpLocPortDesc  = ".1.0.8802.1.1.2.1.3.7.1.3"
-
-	// Additional OIDs
-	sysDesc   = ".1.3.6.1.2.1.1.1.0"       // System Description
-	sysVendor = ".1.3.6.1.4.1.8072.3.2.10" // sysVendor (assuming for the example)
-
-	// Remote LLDP Information OIDs
-	lldpRemChassisID   = ".1.0.8802.1.1.2.1.4.1.1.5"
-	lldpRemPortID      = ".1.0.8802.1.1.2.1.4.1.1.7"
-	lldpRemPortDesc    = ".1.0.8802.1.1.2.1.4.1.1.8"
-	lldpRemSysName     = ".1.0.8802.1.1.2.1.4.1.1.9"
-	lldpRemSysCap      = ".1.0.8802.1.1.2.1.4.1.1.12"
-	lldpRemMgmtAddress = ".1.0.8802.1.1.2.1.4.2.1.4"
-	lldpRemTable       = ".1.0.8802.1.1.2.1.4.1"
-)
-
 func main() {
-	input := "input.csv"
-	output := "lldp_info.csv"
-
-	records, err := readCSV(input)
-	if err != nil {
-		log.Fatalf("Error reading input CSV file: %v", err)
-	}
+	input := flag.String("input", "input.csv", "path to input CSV of targets")
+	output := flag.String("output", "lldp_info.csv", "path to output file")
+	format := flag.String("format", "csv", "output format: csv, json, influx, or prometheus")
+	workers := flag.Int("workers", 32, "number of targets to poll concurrently")
+	credentialsPath := flag.String("v3-config", "", "path to SNMPv3 credentials config (required if any target uses a v3 profile)")
+	topologyDot := flag.String("topology-dot", "", "if set, write the discovered topology as Graphviz DOT to this path")
+	topologyGraphML := flag.String("topology-graphml", "", "if set, write the discovered topology as GraphML to this path")
+	logFormat := flag.String("log-format", "text", "log output format: text, json, or recfile")
+	protocolsFlag := flag.String("protocols", "", "comma-separated discovery protocols to run unconditionally (lldp,cdp,sonmp); default auto-detects by falling back to CDP/SONMP only when LLDP finds nothing")
+	flag.Parse()
 
-	allLocalInfo := make(map[string]map[string]string)
-	allRemoteInfo := make(map[string][]map[string]string)
+	protocols := parseProtocols(*protocolsFlag)
 
-	for _, record := range records {
-		if len(record) < 2 {
-			log.Printf("Skipping invalid record: %v\n", record)
-			continue
-		}
-		target := record[0]
-		community := record[1]
-
-		snmp := initializeSNMP(target, community)
-		defer snmp.Conn.Close()
-
-		localInfo, err := fetchLocalLLDP(snmp)
-		if err != nil {
-			log.Printf("Error fetching local LLDP info for %s: %v\n", target, err)
-			continue
-		}
-
-		remoteInfo, err := fetchRemoteLLDP(snmp)
-		if err != nil {
-			log.Printf("Error fetching remote LLDP info for %s: %v\n", target, err)
-			continue
-		}
-
-		allLocalInfo[target] = localInfo
-		allRemoteInfo[target] = remoteInfo
+	if err := setupLogger(*logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring logging: %v\n", err)
+		os.Exit(1)
 	}
 
-	err = writeBatchCSV(output, allLocalInfo, allRemoteInfo)
-	if err != nil {
-		log.Fatalf("Error writing output CSV file: %v", err)
+	if *workers < 1 {
+		logger.Error("error validating flags", "error", fmt.Errorf("-workers must be at least 1, got %d", *workers))
+		os.Exit(1)
 	}
 
-	log.Printf("LLDP information successfully written to %s", output)
-}
-
-func initializeSNMP(target, community string) *gosnmp.GoSNMP {
-	snmp := &gosnmp.GoSNMP{
-		Target:    target,
-		Port:      161,
-		Community: community,
-		Version:   gosnmp.Version2c,
-		Timeout:   time.Duration(5) * time.Second,
-		Retries:   1,
+	exporter, err := exporterFor(*format)
+	if err != nil {
+		logger.Error("error selecting output format", "error", err)
+		os.Exit(1)
 	}
 
-	err := snmp.Connect()
+	records, err := readCSV(*input)
 	if err != nil {
-		log.Fatalf("Error connecting to target %s: %v", target, err)
+		logger.Error("error reading input CSV file", "path", *input, "error", err)
+		os.Exit(1)
 	}
 
-	return snmp
-}
-
-func fetchLocalLLDP(snmp *gosnmp.GoSNMP) (map[string]string, error) {
-	localOids := []string{lldpLocChassisID, lldpLocSysName, lldpLocPortDesc, sysDesc, sysVendor}
-	localInfo, err := snmp.Get(localOids)
+	targets, err := parseTargets(records)
 	if err != nil {
-		return nil, fmt.Errorf("error getting local LLDP info: %v", err)
+		logger.Error("error parsing input CSV file", "path", *input, "error", err)
+		os.Exit(1)
 	}
 
-	result := make(map[string]string)
-	for _, variable := range localInfo.Variables {
-		value := parseSNMPVariable(variable)
-		switch variable.Name {
-		case lldpLocChassisID:
-			result["Local Chassis ID"] = value
-		case lldpLocSysName:
-			result["Local System Name"] = value
-		case lldpLocPortDesc:
-			result["Local Port Description"] = value
-		case sysDesc:
-			result["System Description"] = value
-		case sysVendor:
-			result["System Vendor"] = value
+	var creds *CredentialStore
+	if *credentialsPath != "" {
+		creds, err = loadCredentials(*credentialsPath)
+		if err != nil {
+			logger.Error("error loading SNMPv3 credentials", "path", *credentialsPath, "error", err)
+			os.Exit(1)
 		}
 	}
-	return result, nil
-}
 
-func fetchRemoteLLDP(snmp *gosnmp.GoSNMP) ([]map[string]string, error) {
-	remoteInfo, err := snmp.WalkAll(lldpRemTable)
-	if err != nil {
-		return nil, fmt.Errorf("error getting remote LLDP info: %v", err)
+	allLocalInfo := make(map[string]*LocalInventory)
+	allRemoteInfo := make(map[string][]RemoteNeighbor)
+	var mu sync.Mutex
+
+	jobs := make(chan Target)
+	var wg sync.WaitGroup
+
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range jobs {
+				localInfo, remoteInfo, err := pollTarget(target, creds, protocols)
+				if err != nil {
+					logger.Warn("error polling target", "target", target.Host, "error", err)
+					continue
+				}
+
+				mu.Lock()
+				allLocalInfo[target.Host] = localInfo
+				allRemoteInfo[target.Host] = remoteInfo
+				mu.Unlock()
+			}
+		}()
 	}
 
-	results := []map[string]string{}
-	current := make(map[string]string)
-
-	log.Println("Starting SNMP walk on remote LLDP table")
-	for _, variable := range remoteInfo {
-		value := parseSNMPVariable(variable)
-		log.Printf("OID: %s, Value: %s", variable.Name, value)
-		switch {
-		case strings.HasPrefix(variable.Name, lldpRemChassisID):
-			current["Remote Chassis ID"] = value
-		case strings.HasPrefix(variable.Name, lldpRemPortID):
-			current["Remote Port ID"] = value
-		case strings.HasPrefix(variable.Name, lldpRemPortDesc):
-			current["Remote Port Description"] = value
-		case strings.HasPrefix(variable.Name, lldpRemSysName):
-			current["Remote System Name"] = value
-		case strings.HasPrefix(variable.Name, lldpRemSysCap):
-			current["Remote System Capabilities"] = value
-		case strings.HasPrefix(variable.Name, lldpRemMgmtAddress):
-			current["Remote Management Address"] = value
-		}
-
-		// Check if we have a complete set and append
-		if hasAllKeys(current) {
-			results = append(results, current)
-			current = make(map[string]string)
-		}
+	for _, target := range targets {
+		jobs <- target
 	}
+	close(jobs)
+	wg.Wait()
 
-	// Append the last set of data if it's not empty
-	if len(current) > 0 {
-		results = append(results, current)
+	err = exporter.Export(*output, allLocalInfo, allRemoteInfo)
+	if err != nil {
+		logger.Error("error writing output file", "path", *output, "error", err)
+		os.Exit(1)
 	}
-	return results, nil
-}
 
-func hasAllKeys(data map[string]string) bool {
-	requiredKeys := []string{
-		"Remote Chassis ID",
-		"Remote Port ID",
-		"Remote Port Description",
-		"Remote System Name",
-		"Remote System Capabilities",
-		"Remote Management Address",
-	}
-	for _, key := range requiredKeys {
-		if _, ok := data[key]; !ok {
-			return false
-		}
-	}
-	return true
-}
+	logger.Info("LLDP information successfully written", "path", *output)
 
-func parseSNMPVariable(variable gosnmp.SnmpPDU) string {
-	switch variable.Type {
-	case gosnmp.OctetString:
-		value := variable.Value.([]byte)
-		// Check if the byte slice contains mostly printable characters
-		if isMostlyPrintable(value) {
-			return string(value)
+	if *topologyDot != "" || *topologyGraphML != "" {
+		if err := writeTopology(allLocalInfo, allRemoteInfo, *topologyDot, *topologyGraphML); err != nil {
+			logger.Warn("error writing topology", "error", err)
 		}
-		// Fallback to hex encoding for non-printable characters
-		return hex.EncodeToString(value)
-	default:
-		if variable.Value == nil {
-			return "<nil>"
-		}
-		return fmt.Sprintf("%v", variable.Value)
 	}
 }
 
-func isMostlyPrintable(data []byte) bool {
-	nonPrintableCount := 0
-	for _, b := range data {
-		if (b < 32 || b > 126) && b != 10 && b != 13 { // Allow newline and carriage return
-			nonPrintableCount++
-		}
-	}
-	// Consider the data printable if more than 90% of the characters are printable
-	return nonPrintableCount < len(data)/10
-}
+// pollTarget connects to a single target and fetches its local and remote
+// LLDP information. Errors are returned rather than fatal so one bad
+// target never aborts the run.
+func pollTarget(target Target, creds *CredentialStore, protocols []string) (*LocalInventory, []RemoteNeighbor, error) {
+	debugSNMP("connecting to target", "target", target.Host, "port", target.Port, "version", target.Version)
 
-func readCSV(filename string) ([][]string, error) {
-	file, err := os.Open(filename)
+	snmp, err := initializeSNMP(target, creds)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer file.Close()
+	defer snmp.Conn.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	localInfo, err := fetchLocalInventory(snmp)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return records, nil
-}
 
-func writeBatchCSV(filename string, localInfo map[string]map[string]string, remoteInfo map[string][]map[string]string) error {
-	file, err := os.Create(filename)
+	remoteInfo, err := fetchRemoteNeighbors(snmp, protocols)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	return localInfo, remoteInfo, nil
+}
 
-	headers := []string{"Type", "Target", "Description", "Value"}
-	writer.Write(headers)
+func initializeSNMP(target Target, creds *CredentialStore) (*gosnmp.GoSNMP, error) {
+	snmp := &gosnmp.GoSNMP{
+		Target:  target.Host,
+		Port:    target.Port,
+		Version: target.Version,
+		Timeout: target.Timeout,
+		Retries: target.Retries,
+	}
 
-	for target, info := range localInfo {
-		for desc, value := range info {
-			writer.Write([]string{"Local", target, desc, value})
+	if target.Version == gosnmp.Version3 {
+		profile, err := creds.lookup(target.V3Profile)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving v3 credentials for target %s: %v", target.Host, err)
 		}
-	}
 
-	for target, infos := range remoteInfo {
-		for _, info := range infos {
-			for desc, value := range info {
-				writer.Write([]string{"Remote", target, desc, value})
-			}
+		params, flags, err := usmSecurityParameters(profile)
+		if err != nil {
+			return nil, fmt.Errorf("error building v3 security parameters for target %s: %v", target.Host, err)
 		}
+
+		snmp.SecurityModel = gosnmp.UserSecurityModel
+		snmp.MsgFlags = flags
+		snmp.SecurityParameters = params
+		snmp.ContextName = profile.ContextName
+	} else {
+		snmp.Community = target.Community
 	}
-	return nil
+
+	if err := snmp.Connect(); err != nil {
+		return nil, fmt.Errorf("error connecting to target %s: %v", target.Host, err)
+	}
+
+	return snmp, nil
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// parseProtocols splits a -protocols flag value ("lldp,cdp,sonmp") into a
+// list of protocol names, trimmed and lowercased. An empty string means
+// "auto-detect": try LLDP first and only fall back to CDP/SONMP if it
+// finds nothing.
+func parseProtocols(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	var protocols []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" {
+			protocols = append(protocols, p)
+		}
+	}
+	return protocols
+}
+
+// fetchRemoteNeighbors runs the requested discovery protocols and merges
+// their neighbors into a single list. With no protocols requested, it
+// auto-detects: LLDP first, falling back to CDP and then SONMP only if
+// the previous protocol found zero neighbors.
+func fetchRemoteNeighbors(snmp *gosnmp.GoSNMP, protocols []string) ([]RemoteNeighbor, error) {
+	if len(protocols) > 0 {
+		var all []RemoteNeighbor
+		for _, protocol := range protocols {
+			neighbors, err := fetchByProtocol(snmp, protocol)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, neighbors...)
+		}
+		return all, nil
+	}
+
+	neighbors, err := fetchRemoteLLDP(snmp)
+	if err != nil {
+		return nil, err
+	}
+	if len(neighbors) > 0 {
+		return neighbors, nil
+	}
+
+	if cdpNeighbors, err := fetchRemoteCDP(snmp); err != nil {
+		logger.Warn("CDP fallback discovery failed", "error", err)
+	} else if len(cdpNeighbors) > 0 {
+		return cdpNeighbors, nil
+	}
+
+	if sonmpNeighbors, err := fetchRemoteSONMP(snmp); err != nil {
+		logger.Warn("SONMP fallback discovery failed", "error", err)
+	} else if len(sonmpNeighbors) > 0 {
+		return sonmpNeighbors, nil
+	}
+
+	return neighbors, nil
+}
+
+func fetchByProtocol(snmp *gosnmp.GoSNMP, protocol string) ([]RemoteNeighbor, error) {
+	switch protocol {
+	case "lldp":
+		return fetchRemoteLLDP(snmp)
+	case "cdp":
+		return fetchRemoteCDP(snmp)
+	case "sonmp":
+		return fetchRemoteSONMP(snmp)
+	default:
+		return nil, fmt.Errorf("unrecognized discovery protocol %q (want lldp, cdp, or sonmp)", protocol)
+	}
+}
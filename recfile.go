@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recfileHandler is a slog.Handler that writes GNU recutils-style
+// records: one blank-line-separated stanza per log event, each a set of
+// "Key: value" lines.
+type recfileHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	attrs []slog.Attr
+}
+
+func newRecfileHandler(w io.Writer) *recfileHandler {
+	return &recfileHandler{w: w, mu: &sync.Mutex{}}
+}
+
+func (h *recfileHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *recfileHandler) Handle(_ context.Context, r slog.Record) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Time: %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Level: %s\n", r.Level)
+	fmt.Fprintf(&sb, "Message: %s\n", r.Message)
+
+	for _, a := range h.attrs {
+		fmt.Fprintf(&sb, "%s: %v\n", a.Key, a.Value.Any())
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fmt.Fprintf(&sb, "%s: %v\n", a.Key, a.Value.Any())
+		return true
+	})
+	sb.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, sb.String())
+	return err
+}
+
+func (h *recfileHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	merged = append(merged, h.attrs...)
+	merged = append(merged, attrs...)
+	return &recfileHandler{w: h.w, mu: h.mu, attrs: merged}
+}
+
+func (h *recfileHandler) WithGroup(string) slog.Handler {
+	// Records are flat key/value stanzas; groups aren't nested.
+	return h
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// SONMP (Nortel/SynOptics topology discovery, s5EnMsTopTable) OIDs, indexed
+// by s5EnMsTopNmmChassisIndex.s5EnMsTopNmmPortIndex. Legacy Nortel/Bay
+// Networks gear that never picked up LLDP still ships this table.
+var (
+	s5EnMsTopTable              = ".1.3.6.1.4.1.45.1.6.13.2.1"
+	s5EnMsTopNmmOtherChassisIdx = ".1.3.6.1.4.1.45.1.6.13.2.1.1.6"
+	s5EnMsTopNmmOtherPortIdx    = ".1.3.6.1.4.1.45.1.6.13.2.1.1.7"
+	s5EnMsTopIpAddr             = ".1.3.6.1.4.1.45.1.6.13.2.1.1.9"
+)
+
+var sonmpColumnOIDs = []string{
+	s5EnMsTopNmmOtherChassisIdx,
+	s5EnMsTopNmmOtherPortIdx,
+	s5EnMsTopIpAddr,
+}
+
+type sonmpColumns struct {
+	otherChassisIdx *gosnmp.SnmpPDU
+	otherPortIdx    *gosnmp.SnmpPDU
+	ipAddr          *gosnmp.SnmpPDU
+}
+
+// fetchRemoteSONMP walks s5EnMsTopTable for devices discovered only via
+// Nortel/SynOptics topology discovery, normalizing the result into the
+// same RemoteNeighbor shape as LLDP and CDP.
+func fetchRemoteSONMP(snmp *gosnmp.GoSNMP) ([]RemoteNeighbor, error) {
+	walk, err := snmp.WalkAll(s5EnMsTopTable)
+	if err != nil {
+		return nil, fmt.Errorf("error getting SONMP topology table: %v", err)
+	}
+
+	buckets := make(map[remoteIndex]*sonmpColumns)
+	var order []remoteIndex
+
+	for i := range walk {
+		variable := &walk[i]
+		debugWalk(fmt.Sprintf("OID: %s, Value: %s", variable.Name, parseSNMPVariable(*variable)))
+
+		column, idx, ok := classifyColumn(variable.Name, sonmpColumnOIDs, 2)
+		if !ok {
+			continue
+		}
+
+		entry, ok := buckets[idx]
+		if !ok {
+			entry = &sonmpColumns{}
+			buckets[idx] = entry
+			order = append(order, idx)
+		}
+
+		switch column {
+		case s5EnMsTopNmmOtherChassisIdx:
+			entry.otherChassisIdx = variable
+		case s5EnMsTopNmmOtherPortIdx:
+			entry.otherPortIdx = variable
+		case s5EnMsTopIpAddr:
+			entry.ipAddr = variable
+		}
+	}
+
+	results := make([]RemoteNeighbor, 0, len(order))
+	for _, idx := range order {
+		results = append(results, decodeSONMPNeighbor(idx, buckets[idx]))
+	}
+	return results, nil
+}
+
+func decodeSONMPNeighbor(idx remoteIndex, cols *sonmpColumns) RemoteNeighbor {
+	neighbor := RemoteNeighbor{
+		Protocol:     "SONMP",
+		LocalPortNum: idx.localPort, // s5EnMsTopNmmPortIndex
+	}
+
+	if cols.otherChassisIdx != nil {
+		neighbor.ChassisID = parseSNMPVariable(*cols.otherChassisIdx)
+	}
+	if cols.otherPortIdx != nil {
+		neighbor.PortID = parseSNMPVariable(*cols.otherPortIdx)
+	}
+	if cols.ipAddr != nil {
+		if raw, ok := cols.ipAddr.Value.([]byte); ok && len(raw) == 4 {
+			neighbor.ManagementAddress = net.IP(raw).String()
+		} else {
+			neighbor.ManagementAddress = parseSNMPVariable(*cols.ipAddr)
+		}
+	}
+
+	return neighbor
+}
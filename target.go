@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// Target describes one row of the input CSV: a host to poll plus any
+// per-target overrides for connection parameters. Community is used for
+// v2c; V3Profile names a profile to resolve from the credentials config
+// when Version is v3.
+type Target struct {
+	Host      string
+	Port      uint16
+	Version   gosnmp.SnmpVersion
+	Community string
+	V3Profile string
+	Timeout   time.Duration
+	Retries   int
+}
+
+const (
+	defaultPort    = 161
+	defaultTimeout = 5 * time.Second
+	defaultRetries = 1
+)
+
+// parseTargets turns CSV rows into Targets. The first two columns
+// (host, community) are required; everything after is an optional
+// override in the fixed order port, version, timeout, retries, v3profile.
+// A blank cell falls back to the default for that field. A malformed row
+// is skipped with a warning rather than aborting the whole batch, so one
+// typo'd field on a single line of a large input CSV doesn't stop every
+// other target from being polled.
+func parseTargets(records [][]string) ([]Target, error) {
+	targets := make([]Target, 0, len(records))
+	for i, record := range records {
+		t, err := parseTargetRow(record)
+		if err != nil {
+			logger.Warn("skipping malformed input row", "row", i, "error", err)
+			continue
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+func parseTargetRow(record []string) (Target, error) {
+	if len(record) < 2 {
+		return Target{}, fmt.Errorf("expected at least host,community columns, got %v", record)
+	}
+
+	t := Target{
+		Host:      strings.TrimSpace(record[0]),
+		Community: strings.TrimSpace(record[1]),
+		Port:      defaultPort,
+		Version:   gosnmp.Version2c,
+		Timeout:   defaultTimeout,
+		Retries:   defaultRetries,
+	}
+
+	if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+		port, err := strconv.ParseUint(strings.TrimSpace(record[2]), 10, 16)
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid port %q: %v", record[2], err)
+		}
+		t.Port = uint16(port)
+	}
+
+	if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+		version, err := parseSNMPVersion(record[3])
+		if err != nil {
+			return Target{}, err
+		}
+		t.Version = version
+	}
+
+	if len(record) > 4 && strings.TrimSpace(record[4]) != "" {
+		secs, err := strconv.Atoi(strings.TrimSpace(record[4]))
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid timeout %q: %v", record[4], err)
+		}
+		t.Timeout = time.Duration(secs) * time.Second
+	}
+
+	if len(record) > 5 && strings.TrimSpace(record[5]) != "" {
+		retries, err := strconv.Atoi(strings.TrimSpace(record[5]))
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid retries %q: %v", record[5], err)
+		}
+		t.Retries = retries
+	}
+
+	if len(record) > 6 {
+		t.V3Profile = strings.TrimSpace(record[6])
+	}
+
+	return t, nil
+}
+
+func parseSNMPVersion(s string) (gosnmp.SnmpVersion, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "v1":
+		return gosnmp.Version1, nil
+	case "", "2c", "v2c":
+		return gosnmp.Version2c, nil
+	case "3", "v3":
+		return gosnmp.Version3, nil
+	default:
+		return 0, fmt.Errorf("unrecognized SNMP version %q", s)
+	}
+}
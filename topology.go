@@ -0,0 +1,249 @@
+package main
+
+import (
+	"os"
+	"sort"
+)
+
+// TopologyNode is one switch discovered either by being polled directly or
+// by being named as a neighbor's chassis ID.
+type TopologyNode struct {
+	ChassisID string
+	SysName   string
+	SysDescr  string
+}
+
+// TopologyEdge is one adjacency between two chassis IDs. It carries both
+// sides' local port so DOT/GraphML output can label the link the same way
+// LLDP describes it: local-port <-> remote-port.
+type TopologyEdge struct {
+	From, To string
+	FromPort string
+	ToPort   string
+}
+
+// Topology is the result of correlating every polled target's local and
+// remote LLDP data into a single adjacency graph.
+type Topology struct {
+	Nodes   map[string]*TopologyNode
+	Edges   []TopologyEdge
+	Orphans []string                     // remote chassis IDs never seen as a polled target's local chassis
+	Cycles  [][]string                   // chassis ID loops
+	Bundles map[[2]string][]TopologyEdge // chassis pairs with more than one link (MLAG/LAG)
+}
+
+// BuildTopology correlates allLocalInfo/allRemoteInfo (as populated by
+// pollTarget across every target) into a deduplicated adjacency graph.
+func BuildTopology(allLocalInfo map[string]*LocalInventory, allRemoteInfo map[string][]RemoteNeighbor) *Topology {
+	topo := &Topology{
+		Nodes:   make(map[string]*TopologyNode),
+		Bundles: make(map[[2]string][]TopologyEdge),
+	}
+
+	localChassisByTarget := make(map[string]string)
+	knownLocalChassis := make(map[string]bool)
+
+	for target, inventory := range allLocalInfo {
+		chassisID := inventory.Scalar["Local Chassis ID"]
+		if chassisID == "" {
+			continue
+		}
+		localChassisByTarget[target] = chassisID
+		knownLocalChassis[chassisID] = true
+		topo.addNode(chassisID, inventory.Scalar["Local System Name"], inventory.Scalar["System Description"])
+	}
+
+	seenEdges := make(map[[4]string]bool)
+
+	for target, neighbors := range allRemoteInfo {
+		fromChassis, ok := localChassisByTarget[target]
+		if !ok {
+			continue
+		}
+
+		for _, neighbor := range neighbors {
+			if neighbor.ChassisID == "" {
+				continue
+			}
+			topo.addNode(neighbor.ChassisID, neighbor.SysName, "")
+
+			// Order each link's endpoints the same way bundleKey does (a < b)
+			// so the same physical link always produces the same From/To,
+			// regardless of which of the two sides' map iteration reaches it
+			// first. Without this, From/To - and so the direction findCycles
+			// follows - would flip arbitrarily between runs on identical input.
+			from, to := fromChassis, neighbor.ChassisID
+			fromPort, toPort := neighbor.LocalPortNum, neighbor.PortID
+			if to < from {
+				from, to = to, from
+				fromPort, toPort = toPort, fromPort
+			}
+
+			key := [4]string{from, to, fromPort, toPort}
+			if seenEdges[key] {
+				continue
+			}
+			seenEdges[key] = true
+
+			edge := TopologyEdge{
+				From:     from,
+				To:       to,
+				FromPort: fromPort,
+				ToPort:   toPort,
+			}
+			topo.Edges = append(topo.Edges, edge)
+			topo.Bundles[bundleKey(edge.From, edge.To)] = append(topo.Bundles[bundleKey(edge.From, edge.To)], edge)
+		}
+	}
+
+	for pair, edges := range topo.Bundles {
+		if len(edges) < 2 {
+			delete(topo.Bundles, pair)
+		}
+	}
+
+	for chassisID := range topo.Nodes {
+		if !knownLocalChassis[chassisID] {
+			topo.Orphans = append(topo.Orphans, chassisID)
+		}
+	}
+	sort.Strings(topo.Orphans)
+
+	topo.Cycles = findCycles(topo.Nodes, topo.Edges)
+
+	return topo
+}
+
+func (t *Topology) addNode(chassisID, sysName, sysDescr string) {
+	node, ok := t.Nodes[chassisID]
+	if !ok {
+		node = &TopologyNode{ChassisID: chassisID}
+		t.Nodes[chassisID] = node
+	}
+	if sysName != "" {
+		node.SysName = sysName
+	}
+	if sysDescr != "" {
+		node.SysDescr = sysDescr
+	}
+}
+
+// bundleKey returns an order-independent key for a chassis pair, so an
+// A->B link and a B->A link land in the same MLAG/LAG bundle.
+func bundleKey(a, b string) [2]string {
+	if a < b {
+		return [2]string{a, b}
+	}
+	return [2]string{b, a}
+}
+
+// findCycles walks the adjacency graph with DFS, reporting each distinct
+// back-edge loop as the chassis IDs on its path. Links are physically
+// undirected (LLDP just reports "my port <-> your port"), so adjacency is
+// built both ways here and traversal skips every link back to the node it
+// was reached from - otherwise every plain A<->B link, and every multi-link
+// LAG/MLAG bundle between the same pair, would register as a trivial
+// two-node cycle. A real ring of three or more switches still
+// produces a back edge to a non-parent ancestor and is reported normally.
+func findCycles(nodes map[string]*TopologyNode, edges []TopologyEdge) [][]string {
+	adjacency := make(map[string][]string)
+	for _, edge := range edges {
+		adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		adjacency[edge.To] = append(adjacency[edge.To], edge.From)
+	}
+
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+	state := make(map[string]int, len(nodes))
+	var path []string
+	var cycles [][]string
+
+	var visit func(chassisID, parent string)
+	visit = func(chassisID, parent string) {
+		state[chassisID] = visiting
+		path = append(path, chassisID)
+
+		for _, next := range adjacency[chassisID] {
+			if next == parent {
+				continue
+			}
+			switch state[next] {
+			case unvisited:
+				visit(next, chassisID)
+			case visiting:
+				cycles = append(cycles, cyclePath(path, next))
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[chassisID] = done
+	}
+
+	// Sort for deterministic traversal order across runs.
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id, "")
+		}
+	}
+	return cycles
+}
+
+// writeTopology builds the adjacency graph from a completed poll run,
+// writes whichever of DOT/GraphML were requested, and logs a summary of
+// cycles, orphans, and MLAG/LAG bundles it found.
+func writeTopology(allLocalInfo map[string]*LocalInventory, allRemoteInfo map[string][]RemoteNeighbor, dotPath, graphMLPath string) error {
+	topo := BuildTopology(allLocalInfo, allRemoteInfo)
+
+	if len(topo.Orphans) > 0 {
+		logger.Info("orphan neighbors never polled directly", "count", len(topo.Orphans), "chassis_ids", topo.Orphans)
+	}
+	if len(topo.Cycles) > 0 {
+		logger.Info("topology cycles detected", "count", len(topo.Cycles), "cycles", topo.Cycles)
+	}
+	for pair, edges := range topo.Bundles {
+		logger.Info("MLAG/LAG bundle detected", "chassis_a", pair[0], "chassis_b", pair[1], "links", len(edges))
+	}
+
+	if dotPath != "" {
+		file, err := os.Create(dotPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := WriteDOT(topo, file); err != nil {
+			return err
+		}
+	}
+
+	if graphMLPath != "" {
+		file, err := os.Create(graphMLPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := WriteGraphML(topo, file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func cyclePath(path []string, start string) []string {
+	for i, id := range path {
+		if id == start {
+			cycle := append([]string{}, path[i:]...)
+			return append(cycle, start)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteDOT emits the topology as a Graphviz DOT digraph, labeling each
+// node with its sysName/sysDescr and each edge with local-port<->remote-port.
+func WriteDOT(topo *Topology, w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph topology {"); err != nil {
+		return err
+	}
+
+	for _, chassisID := range sortedNodeIDs(topo.Nodes) {
+		node := topo.Nodes[chassisID]
+		if _, err := fmt.Fprintf(w, "  %q [label=%q];\n", chassisID, nodeLabel(node)); err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range topo.Edges {
+		label := fmt.Sprintf("%s <-> %s", edge.FromPort, edge.ToPort)
+		if _, err := fmt.Fprintf(w, "  %q -> %q [label=%q];\n", edge.From, edge.To, label); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// WriteGraphML emits the topology as GraphML, the XML graph interchange
+// format most network diagramming tools (yEd, Gephi) can import directly.
+func WriteGraphML(topo *Topology, w io.Writer) error {
+	if _, err := fmt.Fprint(w, graphMLHeader); err != nil {
+		return err
+	}
+
+	for _, chassisID := range sortedNodeIDs(topo.Nodes) {
+		node := topo.Nodes[chassisID]
+		if _, err := fmt.Fprintf(w, "    <node id=%s><data key=\"label\">%s</data></node>\n",
+			xmlAttr(chassisID), xmlEscape(nodeLabel(node))); err != nil {
+			return err
+		}
+	}
+
+	for i, edge := range topo.Edges {
+		label := fmt.Sprintf("%s <-> %s", edge.FromPort, edge.ToPort)
+		if _, err := fmt.Fprintf(w, "    <edge id=\"e%d\" source=%s target=%s><data key=\"label\">%s</data></edge>\n",
+			i, xmlAttr(edge.From), xmlAttr(edge.To), xmlEscape(label)); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprint(w, graphMLFooter)
+	return err
+}
+
+const graphMLHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="label" for="all" attr.name="label" attr.type="string"/>
+  <graph id="topology" edgedefault="directed">
+`
+
+const graphMLFooter = `  </graph>
+</graphml>
+`
+
+func nodeLabel(node *TopologyNode) string {
+	if node.SysName != "" {
+		return node.SysName
+	}
+	if node.SysDescr != "" {
+		return node.SysDescr
+	}
+	return node.ChassisID
+}
+
+func sortedNodeIDs(nodes map[string]*TopologyNode) []string {
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func xmlEscape(s string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+	)
+	return replacer.Replace(s)
+}
+
+// xmlAttr renders s as a double-quoted XML attribute value (XML entity
+// escaping, not Go's %q string-quote escaping, which would leave &/</>
+// unescaped and invalid for GraphML consumers like yEd or Gephi).
+func xmlAttr(s string) string {
+	return `"` + xmlEscape(s) + `"`
+}
@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestFindCyclesSimpleLinkIsNotACycle(t *testing.T) {
+	nodes := map[string]*TopologyNode{
+		"A": {ChassisID: "A"},
+		"B": {ChassisID: "B"},
+	}
+	edges := []TopologyEdge{
+		{From: "A", To: "B", FromPort: "1", ToPort: "1"},
+	}
+
+	if cycles := findCycles(nodes, edges); len(cycles) != 0 {
+		t.Errorf("findCycles() = %v, want no cycles for a single undirected link", cycles)
+	}
+}
+
+func TestFindCyclesDetectsRing(t *testing.T) {
+	nodes := map[string]*TopologyNode{
+		"A": {ChassisID: "A"},
+		"B": {ChassisID: "B"},
+		"C": {ChassisID: "C"},
+	}
+	edges := []TopologyEdge{
+		{From: "A", To: "B"},
+		{From: "B", To: "C"},
+		{From: "C", To: "A"},
+	}
+
+	if cycles := findCycles(nodes, edges); len(cycles) == 0 {
+		t.Fatalf("findCycles() found no cycles for a 3-node ring")
+	}
+}
+
+func TestFindCyclesIgnoresParallelBundleLinks(t *testing.T) {
+	nodes := map[string]*TopologyNode{
+		"A": {ChassisID: "A"},
+		"B": {ChassisID: "B"},
+	}
+	edges := []TopologyEdge{
+		{From: "A", To: "B", FromPort: "1", ToPort: "1"},
+		{From: "A", To: "B", FromPort: "2", ToPort: "2"},
+	}
+
+	if cycles := findCycles(nodes, edges); len(cycles) != 0 {
+		t.Errorf("findCycles() = %v, want no cycles for a 2-link LAG/MLAG bundle", cycles)
+	}
+}
+
+// TestBuildTopologyEdgeDirectionIsCanonical guards against edge direction
+// being decided by which side's map iteration reaches the link first:
+// both targets report the same physical link from opposite ends here, and
+// BuildTopology must fold them into one edge with a deterministic
+// From/To ordering (the same a < b ordering bundleKey uses).
+func TestBuildTopologyEdgeDirectionIsCanonical(t *testing.T) {
+	allLocalInfo := map[string]*LocalInventory{
+		"10.0.0.1": {Scalar: map[string]string{"Local Chassis ID": "zzz-switch"}},
+		"10.0.0.2": {Scalar: map[string]string{"Local Chassis ID": "aaa-switch"}},
+	}
+	allRemoteInfo := map[string][]RemoteNeighbor{
+		"10.0.0.1": {{ChassisID: "aaa-switch", LocalPortNum: "1", PortID: "2"}},
+		"10.0.0.2": {{ChassisID: "zzz-switch", LocalPortNum: "2", PortID: "1"}},
+	}
+
+	topo := BuildTopology(allLocalInfo, allRemoteInfo)
+
+	if len(topo.Edges) != 1 {
+		t.Fatalf("got %d edges, want exactly 1 deduplicated edge, edges: %+v", len(topo.Edges), topo.Edges)
+	}
+	edge := topo.Edges[0]
+	if edge.From != "aaa-switch" || edge.To != "zzz-switch" {
+		t.Errorf("edge = %+v, want From=aaa-switch To=zzz-switch (canonical a < b ordering)", edge)
+	}
+}
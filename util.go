@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+func parseSNMPVariable(variable gosnmp.SnmpPDU) string {
+	switch variable.Type {
+	case gosnmp.OctetString:
+		value := variable.Value.([]byte)
+		// Check if the byte slice contains mostly printable characters
+		if isMostlyPrintable(value) {
+			return string(value)
+		}
+		// Fallback to hex encoding for non-printable characters
+		return hex.EncodeToString(value)
+	default:
+		if variable.Value == nil {
+			return "<nil>"
+		}
+		return fmt.Sprintf("%v", variable.Value)
+	}
+}
+
+func isMostlyPrintable(data []byte) bool {
+	nonPrintableCount := 0
+	for _, b := range data {
+		if (b < 32 || b > 126) && b != 10 && b != 13 { // Allow newline and carriage return
+			nonPrintableCount++
+		}
+	}
+	// Consider the data printable if more than 90% of the characters are
+	// printable. Compared by cross-multiplying rather than dividing
+	// len(data) by 10 first, since that truncates to 0 for any data
+	// shorter than 10 bytes and would reject every short printable value.
+	return nonPrintableCount*10 < len(data)
+}